@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ansiBasicRGB gives the approximate sRGB values for the 16 basic ANSI
+// colors (xterm indices 0-15), used by xterm256ToRGB.
+var ansiBasicRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// xterm256ToRGB returns the approximate sRGB values for xterm 256-color
+// index n, covering the 16 basic colors, the 6x6x6 color cube, and the
+// grayscale ramp.
+func xterm256ToRGB(n int) (r, g, b uint8) {
+	switch {
+	case n < 16:
+		c := ansiBasicRGB[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return levels[(n/36)%6], levels[(n/6)%6], levels[n%6]
+	default:
+		v := uint8(8 + 10*(n-232))
+		return v, v, v
+	}
+}
+
+// srgbToLinear converts an 8-bit sRGB channel to linear light.
+func srgbToLinear(c uint8) float64 {
+	v := float64(c) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts a linear-light channel back to 8-bit sRGB.
+func linearToSRGB(v float64) uint8 {
+	if v <= 0.0031308 {
+		v *= 12.92
+	} else {
+		v = 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	switch {
+	case v < 0:
+		v = 0
+	case v > 1:
+		v = 1
+	}
+	return uint8(math.Round(v * 255))
+}
+
+// colorRGB resolves a lipgloss.Color (an xterm 256-color index, as used
+// throughout this program) to its approximate sRGB value.
+func colorRGB(c lipgloss.Color) (r, g, b uint8) {
+	n, err := strconv.Atoi(string(c))
+	if err != nil {
+		return 255, 255, 255
+	}
+	return xterm256ToRGB(n)
+}
+
+// quantizeTo256 finds the nearest xterm 256-color palette entry to the
+// given sRGB color by brute-force nearest-neighbour search.
+func quantizeTo256(r, g, b uint8) lipgloss.Color {
+	best, bestDist := 0, math.MaxFloat64
+	for n := 0; n < 256; n++ {
+		cr, cg, cb := xterm256ToRGB(n)
+		dr, dg, db := float64(r)-float64(cr), float64(g)-float64(cg), float64(b)-float64(cb)
+		if dist := dr*dr + dg*dg + db*db; dist < bestDist {
+			bestDist, best = dist, n
+		}
+	}
+	return lipgloss.Color(strconv.Itoa(best))
+}
+
+// blendLinear averages a set of xterm 256-color sparks in linear RGB space
+// and quantises the result back to the nearest palette entry. Blending in
+// linear space (rather than directly averaging sRGB) keeps mixed colors
+// from looking muddier than either input.
+func blendLinear(colors []lipgloss.Color) lipgloss.Color {
+	if len(colors) == 0 {
+		return ""
+	}
+	var rl, gl, bl float64
+	for _, c := range colors {
+		r, g, b := colorRGB(c)
+		rl += srgbToLinear(r)
+		gl += srgbToLinear(g)
+		bl += srgbToLinear(b)
+	}
+	n := float64(len(colors))
+	return quantizeTo256(linearToSRGB(rl/n), linearToSRGB(gl/n), linearToSRGB(bl/n))
+}
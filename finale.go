@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// maxFinaleStage caps how many generations of payload rockets a finale
+// rocket carries, keeping the cascade of chained explosions finite.
+const maxFinaleStage = 2
+
+// newFinaleRocket launches a finale rocket from the bottom of the screen
+// carrying a dense, multi-stage payload of secondary rockets.
+func newFinaleRocket(x, height int) rocket {
+	r := newRocket(float64(x), float64(height-1), 0, -1.6, colors[rand.Intn(len(colors))])
+	r.finale = true
+	r.payload = finalePayload(1)
+	return r
+}
+
+// finalePayload builds the secondary rockets a finale burst launches from
+// its burst point, recursing up to maxFinaleStage generations deep.
+func finalePayload(stage int) []rocket {
+	n := rand.Intn(3) + 2 // 2 to 4 children per stage
+	children := make([]rocket, 0, n)
+	for i := 0; i < n; i++ {
+		angle := rand.Float64() * 2 * math.Pi
+		speed := rand.Float64()*0.8 + 0.6
+		child := rocket{
+			char:      rocketChar,
+			color:     colors[rand.Intn(len(colors))],
+			stage:     stage,
+			fuseTicks: rand.Intn(6) + 8,
+			finale:    true,
+			launchVX:  math.Cos(angle) * speed,
+			launchVY:  math.Sin(angle) * speed,
+		}
+		if stage < maxFinaleStage {
+			child.payload = finalePayload(stage + 1)
+		}
+		children = append(children, child)
+	}
+	return children
+}
+
+// largeShellTypes are the shell kinds biased toward for finale bursts:
+// bigger and longer-lived than the default mix.
+var largeShellTypes = []ShellType{ShellWillow, ShellChrysanthemum, ShellPalm}
+
+// biasedLargeShellType picks a shell type biased toward larger, longer-lived
+// patterns, used for finale rockets.
+func biasedLargeShellType() ShellType {
+	return largeShellTypes[rand.Intn(len(largeShellTypes))]
+}
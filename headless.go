@@ -0,0 +1,62 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/pehlicd/firework/simulation"
+)
+
+// Fixed dimensions used for headless frame generation, where there is no
+// real terminal to query a size from.
+const (
+	headlessWidth  = 80
+	headlessHeight = 24
+)
+
+// runHeadless drives model m through n ticks without opening the alt
+// screen, recording every frame to path. It spawns rockets itself every 2
+// to 16 ticks rather than relying on bubbletea's real-time newFirework
+// scheduling, so the whole run is deterministic under --seed.
+func runHeadless(n int, path string, m model) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	m.recorder = NewRecorder(f)
+	m.width, m.height = headlessWidth, headlessHeight
+	m.system.Frame = simulation.Frame{Width: m.width, Height: m.height}
+	m.buf = make([][]string, m.height)
+	for i := range m.buf {
+		m.buf[i] = make([]string, m.width)
+	}
+
+	// There's no real ticker driving these frames, so stamp them on a
+	// synthetic clock advancing one tick (1/fps) at a time instead of
+	// time.Now(), or the whole cast would compress to however long this
+	// loop actually took to run.
+	clockStart := time.Now()
+	frame := 0
+	m.recorder.now = func() time.Time {
+		return clockStart.Add(time.Duration(frame) * time.Second / fps)
+	}
+
+	nextFirework := 0
+	for i := 0; i < n; i++ {
+		frame = i
+		if i >= nextFirework {
+			r := newRocket(float64(rand.Intn(m.width)), float64(m.height-1), 0, -1.5, colors[rand.Intn(len(colors))])
+			m.rockets = append(m.rockets, r)
+			nextFirework = i + rand.Intn(15) + 2
+		}
+
+		next, _ := m.Update(tickMsg(time.Time{}))
+		m = next.(model)
+		m.View() // rendering records the frame as a side effect
+	}
+
+	return nil
+}
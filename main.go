@@ -1,17 +1,26 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"math"
 	"math/rand"
 	"os"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/harmonica"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pehlicd/firework/simulation"
 )
 
+const fps = 15
+
+// defaultParticleBudget caps how many live particles the system will carry
+// at once, so a finale's cascade of bursts can't choke the terminal.
+const defaultParticleBudget = 4000
+
 var (
 	rocketChar = "↑"
 	colors     = []lipgloss.Color{
@@ -24,32 +33,118 @@ var (
 	}
 )
 
-// Represents a single particle in a firework explosion.
-type particle struct {
-	x, y     float64
-	vx, vy   float64
-	lifespan int
-	char     string
-	color    lipgloss.Color
+// Represents a firework rocket climbing toward its burst height, or a
+// staged child waiting to be launched from its parent's burst point.
+type rocket struct {
+	physics *harmonica.Projectile
+	char    string
+	color   lipgloss.Color
+
+	// launchVX/launchVY record the rocket's ascent velocity in cells/tick,
+	// so a payload spec can be re-launched from its parent's burst point
+	// once its fuse runs out.
+	launchVX, launchVY float64
+
+	// stage is 0 for rockets launched from the ground, and >0 for payload
+	// rockets spawned by a parent's burst. fuseTicks counts down once a
+	// staged rocket is airborne; it bursts when the fuse reaches zero
+	// instead of using the ground rocket's height-based trigger.
+	stage     int
+	fuseTicks int
+
+	// payload holds the specs for secondary rockets to launch from this
+	// rocket's burst point: only color/stage/fuseTicks/finale/launchV*/
+	// payload are meaningful until the spec is turned into a real rocket
+	// via newRocket, at which point physics is (re)built from scratch.
+	payload []rocket
+
+	// finale marks rockets launched by the finale trigger, biasing their
+	// burst toward larger, longer-lived shell types.
+	finale bool
+
+	// offset exists purely so a terminal resize can reposition a live
+	// rocket without reconstructing its projectile, the same trick
+	// simulation.Particle uses.
+	offset simulation.Offset
 }
 
-// Represents a firework rocket.
-type rocket struct {
-	x, y  int
-	vy    float64
-	char  string
-	color lipgloss.Color
+// x and y return the rocket's current position as whole cells.
+func (r *rocket) x() int {
+	x, _ := r.position()
+	return int(x)
+}
+
+func (r *rocket) y() int {
+	_, y := r.position()
+	return int(y)
+}
+
+// position returns the rocket's current float coordinates, physics plus
+// any resize offset.
+func (r *rocket) position() (x, y float64) {
+	return r.offset.Position(r.physics)
+}
+
+// rescale repositions the rocket proportionally to a frame resize, then
+// clamps it within [0, width) x [0, height) so it can't end up off-screen.
+func (r *rocket) rescale(scaleX, scaleY float64, width, height int) {
+	r.offset.Rescale(r.physics, scaleX, scaleY)
+	r.offset.Clamp(r.physics, width, height)
+}
+
+// newRocket launches a rocket from (x, y) at (vx, vy) cells per tick,
+// matching the original integer-stepped motion but driven by harmonica so
+// the physics layer is shared with particles.
+func newRocket(x, y, vx, vy float64, color lipgloss.Color) rocket {
+	return rocket{
+		physics: harmonica.NewProjectile(
+			harmonica.FPS(fps),
+			harmonica.Point{X: x, Y: y},
+			harmonica.Vector{X: vx * fps, Y: vy * fps},
+			harmonica.Vector{},
+		),
+		char:     rocketChar,
+		color:    color,
+		launchVX: vx,
+		launchVY: vy,
+	}
+}
+
+// launch turns a payload spec into a live rocket airborne from (x, y),
+// carrying over its color, staging, fuse, and any deeper payload.
+func launch(spec rocket, x, y float64) rocket {
+	r := newRocket(x, y, spec.launchVX, spec.launchVY, spec.color)
+	r.stage = spec.stage
+	r.fuseTicks = spec.fuseTicks
+	r.payload = spec.payload
+	r.finale = spec.finale
+	return r
 }
 
 // The main model for our application.
 type model struct {
-	width     int
-	height    int
-	mouseX    int
-	mouseY    int
-	rockets   []rocket
-	particles []particle
-	quitting  bool
+	width    int
+	height   int
+	mouseX   int
+	mouseY   int
+	rockets  []rocket
+	system   *simulation.System
+	quitting bool
+
+	// shellOverride, when non-nil, forces every burst to use this shell
+	// type instead of picking one at random.
+	shellOverride *ShellType
+
+	// renderMode controls how particles are composited into the terminal
+	// buffer; it defaults to RenderASCII for compatibility.
+	renderMode RenderMode
+
+	// recorder, when non-nil, appends every rendered frame to a .cast file.
+	recorder *Recorder
+
+	// buf is the reusable render buffer, sized to width x height. It's
+	// reallocated only when the terminal dimensions change.
+	buf [][]string
 }
 
 // A message to signal a tick in our animation.
@@ -58,6 +153,20 @@ type tickMsg time.Time
 // A message to create a new firework.
 type newFireworkMsg struct{}
 
+// A message that spawns one finale rocket and, if any are left, schedules
+// the next one.
+type finaleMsg struct {
+	remaining int
+}
+
+// finale schedules the next finale rocket a short, random delay from now,
+// spreading the whole barrage out over a few seconds.
+func finale(remaining int) tea.Cmd {
+	return tea.Tick(time.Duration(rand.Intn(80)+20)*time.Millisecond, func(t time.Time) tea.Msg {
+		return finaleMsg{remaining: remaining}
+	})
+}
+
 // Creates a new firework at a random location at the bottom of the screen.
 func newFirework() tea.Cmd {
 	// Schedule the next firework at a random interval
@@ -68,7 +177,7 @@ func newFirework() tea.Cmd {
 
 // Sends a tick message every frame for animation updates.
 func tick() tea.Cmd {
-	return tea.Tick(time.Second/15, func(t time.Time) tea.Msg {
+	return tea.Tick(time.Second/fps, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
@@ -76,10 +185,10 @@ func tick() tea.Cmd {
 // Returns the initial model.
 func initialModel() model {
 	return model{
-		mouseX:    -1, // Initialize cursor off-screen
-		mouseY:    -1,
-		rockets:   []rocket{},
-		particles: []particle{},
+		mouseX:  -1, // Initialize cursor off-screen
+		mouseY:  -1,
+		rockets: []rocket{},
+		system:  simulation.NewSystem(simulation.Frame{}, defaultParticleBudget),
 	}
 }
 
@@ -89,12 +198,70 @@ func (m model) Init() tea.Cmd {
 	return tea.Batch(tick(), newFirework())
 }
 
+// explode bursts a rocket into a shell of particles sharing its color, then
+// launches any payload rockets from the burst point. The shell type is
+// randomised per rocket (biased toward bigger, longer-lived shells for
+// finale rockets) unless the user forced one via --shell. Particle spawning
+// stops once the system hits its budget: m.system.Spawn enforces that here,
+// and System.Update applies the same cap to particles a crossette shell
+// later splits into.
+func (m *model) explode(r rocket) {
+	kind := randomShellType()
+	if r.finale {
+		kind = biasedLargeShellType()
+	}
+	if m.shellOverride != nil {
+		kind = *m.shellOverride
+	}
+
+	x, y := float64(r.x()), float64(r.y())
+	for _, p := range spawnShell(kind, x, y, r.color) {
+		m.system.Spawn(p)
+	}
+
+	for _, spec := range r.payload {
+		m.rockets = append(m.rockets, launch(spec, x, y))
+	}
+}
+
+// rescale repositions every live rocket and particle proportionally to a
+// resize from (oldWidth, oldHeight) to the model's current dimensions,
+// clamping anything that would otherwise land outside the new bounds.
+func (m *model) rescale(oldWidth, oldHeight int) {
+	if oldWidth <= 1 || oldHeight <= 1 {
+		return
+	}
+	scaleX := float64(m.width-1) / float64(oldWidth-1)
+	scaleY := float64(m.height-1) / float64(oldHeight-1)
+
+	for i := range m.rockets {
+		m.rockets[i].rescale(scaleX, scaleY, m.width, m.height)
+	}
+	for _, p := range m.system.Particles {
+		p.Rescale(scaleX, scaleY)
+		p.Clamp(m.width, m.height)
+	}
+}
+
 // The Update function is called when a message is received.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
+		oldWidth, oldHeight := m.width, m.height
 		m.width = msg.Width
 		m.height = msg.Height
+		m.system.Frame = simulation.Frame{Width: m.width, Height: m.height}
+
+		if oldWidth > 0 && oldHeight > 0 && (oldWidth != m.width || oldHeight != m.height) {
+			m.rescale(oldWidth, oldHeight)
+		}
+
+		if len(m.buf) != m.height || (m.height > 0 && len(m.buf[0]) != m.width) {
+			m.buf = make([][]string, m.height)
+			for i := range m.buf {
+				m.buf[i] = make([]string, m.width)
+			}
+		}
 		return m, nil
 
 	case tea.KeyMsg:
@@ -102,6 +269,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "q", "ctrl+c":
 			m.quitting = true
 			return m, tea.Quit
+		case "f":
+			if m.width == 0 || m.height == 0 {
+				return m, nil
+			}
+			count := rand.Intn(21) + 20 // 20 to 40 rockets
+			return m, finale(count)
 		}
 
 	case tea.MouseMsg:
@@ -114,13 +287,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.width == 0 || m.height == 0 {
 				return m, nil
 			}
-			r := rocket{
-				x:     msg.X,
-				y:     m.height - 1,
-				vy:    -1.5,
-				char:  rocketChar,
-				color: colors[rand.Intn(len(colors))],
-			}
+			r := newRocket(float64(msg.X), float64(m.height-1), 0, -1.5, colors[rand.Intn(len(colors))])
 			m.rockets = append(m.rockets, r)
 		}
 		return m, nil
@@ -131,17 +298,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, newFirework()
 		}
 		// Create a new rocket when a newFireworkMsg is received
-		r := rocket{
-			x:     rand.Intn(m.width),
-			y:     m.height - 1,
-			vy:    -1.5,
-			char:  rocketChar,
-			color: colors[rand.Intn(len(colors))],
-		}
+		r := newRocket(float64(rand.Intn(m.width)), float64(m.height-1), 0, -1.5, colors[rand.Intn(len(colors))])
 		m.rockets = append(m.rockets, r)
 		// Schedule the next firework
 		return m, newFirework()
 
+	case finaleMsg:
+		if msg.remaining <= 0 || m.width == 0 || m.height == 0 {
+			return m, nil
+		}
+		r := newFinaleRocket(rand.Intn(m.width), m.height)
+		m.rockets = append(m.rockets, r)
+		return m, finale(msg.remaining - 1)
+
 	case tickMsg:
 		// Don't run animation logic until we know the screen size.
 		if m.width == 0 || m.height == 0 {
@@ -150,46 +319,36 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		var updatedRockets []rocket
 		for _, r := range m.rockets {
+			// Staged rockets burst when their fuse runs out instead of at
+			// a height threshold.
+			if r.stage > 0 {
+				if r.fuseTicks > 0 {
+					r.fuseTicks--
+					r.physics.Update()
+					updatedRockets = append(updatedRockets, r)
+				} else {
+					n := len(m.rockets)
+					m.explode(r)
+					// Keep the payload rockets explode just launched.
+					updatedRockets = append(updatedRockets, m.rockets[n:]...)
+				}
+				continue
+			}
+
 			// Check if the rocket should explode
 			// It explodes if it reaches a certain height or randomly
-			if r.y < m.height/3 || (r.y < m.height*2/3 && rand.Float64() < 0.1) {
-				// Explode! Create particles
-				numParticles := rand.Intn(20) + 30 // 30 to 49 particles
-				for i := 0; i < numParticles; i++ {
-					angle := (2 * math.Pi / float64(numParticles)) * float64(i)
-					speed := rand.Float64()*2.5 + 1.0
-					p := particle{
-						x:        float64(r.x),
-						y:        float64(r.y),
-						vx:       math.Cos(angle) * speed,
-						vy:       math.Sin(angle) * speed * 0.5,
-						lifespan: rand.Intn(20) + 15,
-						char:     "*",
-						color:    r.color,
-					}
-					m.particles = append(m.particles, p)
-				}
+			y := r.y()
+			if y < m.height/3 || (y < m.height*2/3 && rand.Float64() < 0.1) {
+				n := len(m.rockets)
+				m.explode(r)
+				updatedRockets = append(updatedRockets, m.rockets[n:]...)
 			} else {
-				// Otherwise, keep the rocket moving upwards
-				r.y += int(r.vy)
+				r.physics.Update()
 				updatedRockets = append(updatedRockets, r)
 			}
 		}
 		m.rockets = updatedRockets
-
-		var updatedParticles []particle
-		for _, p := range m.particles {
-			p.x += p.vx
-			p.y += p.vy
-			p.vy += 0.08
-			p.lifespan--
-
-			// Keep the particle if it's still alive
-			if p.lifespan > 0 {
-				updatedParticles = append(updatedParticles, p)
-			}
-		}
-		m.particles = updatedParticles
+		m.system.Update()
 
 		// Continue the animation ticker
 		return m, tick()
@@ -208,39 +367,25 @@ func (m model) View() string {
 		return "Loading..."
 	}
 
-	// Create a 2D slice to act as a screen buffer.
-	// It stores the final, styled string for each cell.
-	buffer := make([][]string, m.height)
-	for i := range buffer {
-		buffer[i] = make([]string, m.width)
-		for j := range buffer[i] {
-			buffer[i][j] = " "
-		}
+	// Start from the particle system's rendered buffer, then layer rockets
+	// and the mouse cursor on top of it. m.buf is reused across frames and
+	// reallocated by Update only when the terminal is resized.
+	var buffer [][]string
+	switch m.renderMode {
+	case RenderBraille:
+		buffer = renderBraille(m.buf, m.system.Particles, m.width, m.height)
+	case RenderHalfBlock:
+		buffer = renderHalfBlock(m.buf, m.system.Particles, m.width, m.height)
+	default:
+		buffer = m.system.Render(m.buf)
 	}
 
 	// Draw rockets into the buffer
 	for _, r := range m.rockets {
-		if r.y >= 0 && r.y < m.height && r.x >= 0 && r.x < m.width {
+		x, y := r.x(), r.y()
+		if y >= 0 && y < m.height && x >= 0 && x < m.width {
 			style := lipgloss.NewStyle().Foreground(r.color)
-			buffer[r.y][r.x] = style.Render(r.char)
-		}
-	}
-
-	// Draw particles into the buffer
-	for _, p := range m.particles {
-		row, col := int(p.y), int(p.x)
-		if row >= 0 && row < m.height && col >= 0 && col < m.width {
-			// Fade out particles as they die
-			alpha := float64(p.lifespan) / 35.0
-			if alpha < 0.5 {
-				p.char = "."
-			}
-			if alpha < 0.2 {
-				p.char = " "
-			}
-
-			style := lipgloss.NewStyle().Foreground(p.color)
-			buffer[row][col] = style.Render(p.char)
+			buffer[y][x] = style.Render(r.char)
 		}
 	}
 
@@ -260,16 +405,74 @@ func (m model) View() string {
 		b.WriteString(strings.Join(buffer[i], ""))
 		b.WriteString("\n")
 	}
-	quitMsg := "Click to launch a firework! Press 'q' to quit."
+	quitMsg := "Click to launch a firework! Press 'f' for a finale, 'q' to quit."
 	b.WriteString(lipgloss.NewStyle().Faint(true).Render(quitMsg))
 
-	return b.String()
+	output := b.String()
+	if m.recorder != nil {
+		_ = m.recorder.Frame(m.width, m.height, output)
+	}
+	return output
 }
 
 func main() {
-	rand.New(rand.NewSource(time.Now().UnixNano()))
+	shellFlag := flag.String("shell", "", "force every explosion to use this shell type (peony, ring, willow, chrysanthemum, palm, crossette)")
+	renderFlag := flag.String("render", "ascii", "particle render mode (ascii, braille, halfblock)")
+	seedFlag := flag.Int64("seed", time.Now().UnixNano(), "seed for the random number generator, for reproducible runs")
+	recordFlag := flag.String("record", "", "record the session to this asciinema .cast file")
+	replayFlag := flag.String("replay", "", "replay a previously recorded .cast file instead of running live")
+	framesFlag := flag.Int("frames", 0, "headless mode: write this many frames to --record and exit, without opening the alt screen")
+	budgetFlag := flag.Int("budget", defaultParticleBudget, "maximum number of live particles the system may hold at once")
+	flag.Parse()
+
+	rand.Seed(*seedFlag)
+
+	if *replayFlag != "" {
+		runReplay(*replayFlag)
+		return
+	}
+
+	m := initialModel()
+	m.system.Budget = *budgetFlag
+	if *shellFlag != "" {
+		kind, ok := parseShellType(*shellFlag)
+		if !ok {
+			fmt.Printf("unknown shell type %q\n", *shellFlag)
+			os.Exit(1)
+		}
+		m.shellOverride = &kind
+	}
+
+	renderMode, ok := parseRenderMode(*renderFlag)
+	if !ok {
+		fmt.Printf("unknown render mode %q\n", *renderFlag)
+		os.Exit(1)
+	}
+	m.renderMode = renderMode
+
+	if *framesFlag > 0 {
+		if *recordFlag == "" {
+			fmt.Println("--frames requires --record")
+			os.Exit(1)
+		}
+		if err := runHeadless(*framesFlag, *recordFlag, m); err != nil {
+			fmt.Printf("failed to write cast file: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *recordFlag != "" {
+		f, err := os.Create(*recordFlag)
+		if err != nil {
+			fmt.Printf("failed to create cast file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		m.recorder = NewRecorder(f)
+	}
 
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen(), tea.WithMouseAllMotion())
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseAllMotion())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Kaboom, there's been an error: %v", err)
 		os.Exit(1)
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// castHeader is the first line of an asciinema v2 .cast file.
+type castHeader struct {
+	Version   int   `json:"version"`
+	Width     int   `json:"width"`
+	Height    int   `json:"height"`
+	Timestamp int64 `json:"timestamp"`
+}
+
+// castFrame is one decoded `[time, "o", data]` output event from a .cast
+// file.
+type castFrame struct {
+	Time float64
+	Data string
+}
+
+// Recorder writes rendered frames to an asciinema v2 .cast stream. The
+// header is written lazily, on the first Frame call, once the terminal's
+// dimensions are known.
+type Recorder struct {
+	w           io.Writer
+	start       time.Time
+	wroteHeader bool
+
+	// now returns the timestamp to stamp the next frame with. It defaults
+	// to time.Now, but headless mode overrides it with a synthetic clock
+	// so a cast rendered without a real-time ticker still plays back at
+	// the intended frame rate instead of compressing to however long the
+	// render loop actually took.
+	now func() time.Time
+}
+
+// NewRecorder returns a Recorder that writes to w, stamping frames with
+// the wall clock.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w, now: time.Now}
+}
+
+// Frame appends one rendered frame to the cast stream, stamped with the
+// time elapsed since the first frame.
+func (r *Recorder) Frame(width, height int, data string) error {
+	now := r.now()
+	if !r.wroteHeader {
+		r.start = now
+		header, err := json.Marshal(castHeader{Version: 2, Width: width, Height: height, Timestamp: now.Unix()})
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(r.w, string(header)); err != nil {
+			return err
+		}
+		r.wroteHeader = true
+	}
+
+	event, err := json.Marshal([]any{now.Sub(r.start).Seconds(), "o", data})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(r.w, string(event))
+	return err
+}
+
+// loadCast reads an asciinema v2 .cast file from disk.
+func loadCast(path string) (castHeader, []castFrame, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return castHeader{}, nil, err
+	}
+	defer f.Close()
+	return parseCast(f)
+}
+
+// parseCast decodes an asciinema v2 .cast stream into its header and
+// output frames, skipping any non-output events.
+func parseCast(r io.Reader) (castHeader, []castFrame, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var header castHeader
+	var frames []castFrame
+	sawHeader := false
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if !sawHeader {
+			if err := json.Unmarshal(line, &header); err != nil {
+				return header, nil, fmt.Errorf("parsing cast header: %w", err)
+			}
+			sawHeader = true
+			continue
+		}
+
+		var event [3]json.RawMessage
+		if err := json.Unmarshal(line, &event); err != nil {
+			return header, nil, fmt.Errorf("parsing cast event: %w", err)
+		}
+		var t float64
+		var kind, data string
+		if err := json.Unmarshal(event[0], &t); err != nil {
+			return header, nil, err
+		}
+		if err := json.Unmarshal(event[1], &kind); err != nil {
+			return header, nil, err
+		}
+		if err := json.Unmarshal(event[2], &data); err != nil {
+			return header, nil, err
+		}
+		if kind == "o" {
+			frames = append(frames, castFrame{Time: t, Data: data})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return header, nil, err
+	}
+	if !sawHeader {
+		return header, nil, fmt.Errorf("cast file has no header")
+	}
+	return header, frames, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRecorderFrameFormat(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+
+	if err := rec.Frame(80, 24, "frame one"); err != nil {
+		t.Fatalf("Frame: %v", err)
+	}
+	if err := rec.Frame(80, 24, "frame two"); err != nil {
+		t.Fatalf("Frame: %v", err)
+	}
+
+	header, frames, err := parseCast(&buf)
+	if err != nil {
+		t.Fatalf("parseCast: %v", err)
+	}
+	if header.Version != 2 || header.Width != 80 || header.Height != 24 {
+		t.Fatalf("unexpected header: %+v", header)
+	}
+	if len(frames) != 2 {
+		t.Fatalf("expected 2 frames, got %d", len(frames))
+	}
+	if frames[0].Data != "frame one" || frames[1].Data != "frame two" {
+		t.Fatalf("unexpected frame data: %+v", frames)
+	}
+	if frames[1].Time < frames[0].Time {
+		t.Fatalf("expected frame timestamps to be non-decreasing, got %v then %v", frames[0].Time, frames[1].Time)
+	}
+}
+
+func TestParseCastSkipsNonOutputEvents(t *testing.T) {
+	const cast = `{"version":2,"width":10,"height":5,"timestamp":0}
+[0.1,"i","ignored input event"]
+[0.2,"o","visible frame"]
+`
+	header, frames, err := parseCast(bytes.NewBufferString(cast))
+	if err != nil {
+		t.Fatalf("parseCast: %v", err)
+	}
+	if header.Width != 10 || header.Height != 5 {
+		t.Fatalf("unexpected header: %+v", header)
+	}
+	if len(frames) != 1 || frames[0].Data != "visible frame" {
+		t.Fatalf("expected only the output event to survive, got %+v", frames)
+	}
+}
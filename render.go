@@ -0,0 +1,151 @@
+package main
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pehlicd/firework/simulation"
+)
+
+// RenderMode selects how particles are composited into terminal cells.
+type RenderMode int
+
+const (
+	RenderASCII RenderMode = iota
+	RenderBraille
+	RenderHalfBlock
+)
+
+// renderModeNames maps a RenderMode to its CLI/display name, in declaration
+// order.
+var renderModeNames = [...]string{"ascii", "braille", "halfblock"}
+
+// String returns the render mode's CLI name.
+func (m RenderMode) String() string {
+	if int(m) < 0 || int(m) >= len(renderModeNames) {
+		return "unknown"
+	}
+	return renderModeNames[m]
+}
+
+// parseRenderMode looks up a RenderMode by its CLI name.
+func parseRenderMode(name string) (RenderMode, bool) {
+	for i, n := range renderModeNames {
+		if n == name {
+			return RenderMode(i), true
+		}
+	}
+	return 0, false
+}
+
+// brailleDotBit maps a particle's position within a cell's 2x4 sub-grid
+// (row 0-3, column 0-1) to its Unicode braille dot bit.
+var brailleDotBit = [4][2]byte{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// renderBraille composes particle positions into buf as a Unicode Braille
+// buffer (U+2800-U+28FF) at 2x4 sub-cell resolution, blending every
+// particle that lands in a cell and quantising the blend to the nearest
+// palette entry. buf is cleared and reused in place.
+func renderBraille(buf [][]string, particles []*simulation.Particle, width, height int) [][]string {
+	type cell struct {
+		mask   byte
+		colors []lipgloss.Color
+	}
+	cells := make([][]cell, height)
+	for i := range cells {
+		cells[i] = make([]cell, width)
+	}
+
+	for _, p := range particles {
+		x, y := p.Position()
+		col, row := int(x), int(y)
+		if row < 0 || row >= height || col < 0 || col >= width {
+			continue
+		}
+		subCol := clampInt(int((x-float64(col))*2), 0, 1)
+		subRow := clampInt(int((y-float64(row))*4), 0, 3)
+
+		c := &cells[row][col]
+		c.mask |= brailleDotBit[subRow][subCol]
+		c.colors = append(c.colors, p.Color)
+	}
+
+	for row := range buf {
+		for col := range buf[row] {
+			c := cells[row][col]
+			if c.mask == 0 {
+				buf[row][col] = " "
+				continue
+			}
+			glyph := string(rune(0x2800 + int(c.mask)))
+			style := lipgloss.NewStyle().Foreground(blendLinear(c.colors))
+			buf[row][col] = style.Render(glyph)
+		}
+	}
+	return buf
+}
+
+// renderHalfBlock composes particle positions into buf at double vertical
+// resolution using ▀/▄ half-block glyphs, coloring the top and bottom
+// halves of each cell independently. buf is cleared and reused in place.
+func renderHalfBlock(buf [][]string, particles []*simulation.Particle, width, height int) [][]string {
+	type half struct {
+		colors []lipgloss.Color
+	}
+	top := make([][]half, height)
+	bottom := make([][]half, height)
+	for i := range top {
+		top[i] = make([]half, width)
+		bottom[i] = make([]half, width)
+	}
+
+	for _, p := range particles {
+		x, y := p.Position()
+		col := int(x)
+		subRow := int(y * 2)
+		row := subRow / 2
+		if row < 0 || row >= height || col < 0 || col >= width {
+			continue
+		}
+		if subRow%2 == 0 {
+			top[row][col].colors = append(top[row][col].colors, p.Color)
+		} else {
+			bottom[row][col].colors = append(bottom[row][col].colors, p.Color)
+		}
+	}
+
+	for row := range buf {
+		for col := range buf[row] {
+			t, b := top[row][col], bottom[row][col]
+			switch {
+			case len(t.colors) == 0 && len(b.colors) == 0:
+				buf[row][col] = " "
+			case len(b.colors) == 0:
+				style := lipgloss.NewStyle().Foreground(blendLinear(t.colors))
+				buf[row][col] = style.Render("▀")
+			case len(t.colors) == 0:
+				style := lipgloss.NewStyle().Foreground(blendLinear(b.colors))
+				buf[row][col] = style.Render("▄")
+			default:
+				style := lipgloss.NewStyle().Foreground(blendLinear(t.colors)).Background(blendLinear(b.colors))
+				buf[row][col] = style.Render("▀")
+			}
+		}
+	}
+	return buf
+}
+
+func clampInt(v, lo, hi int) int {
+	switch {
+	case v < lo:
+		return lo
+	case v > hi:
+		return hi
+	default:
+		return v
+	}
+}
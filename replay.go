@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runReplay plays back a previously recorded .cast file and exits.
+func runReplay(path string) {
+	header, frames, err := loadCast(path)
+	if err != nil {
+		fmt.Printf("failed to load cast file: %v\n", err)
+		os.Exit(1)
+	}
+	if len(frames) == 0 {
+		fmt.Println("cast file has no frames to replay")
+		os.Exit(1)
+	}
+
+	m := replayModel{frames: frames, width: header.Width, height: header.Height}
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		fmt.Printf("Kaboom, there's been an error: %v", err)
+		os.Exit(1)
+	}
+}
+
+// replayModel plays back a sequence of previously recorded frames, ignoring
+// all physics and randomness: it is a pure viewer for a .cast file.
+type replayModel struct {
+	frames        []castFrame
+	idx           int
+	width, height int
+	quitting      bool
+}
+
+// replayTickMsg advances the replay to its next recorded frame.
+type replayTickMsg struct{}
+
+// scheduleNext waits for however long the original recording spent on the
+// current frame before advancing, so playback matches the live timing.
+func (m replayModel) scheduleNext() tea.Cmd {
+	if m.idx >= len(m.frames)-1 {
+		return nil
+	}
+	delay := m.frames[m.idx+1].Time - m.frames[m.idx].Time
+	if delay < 0 {
+		delay = 0
+	}
+	return tea.Tick(time.Duration(delay*float64(time.Second)), func(t time.Time) tea.Msg {
+		return replayTickMsg{}
+	})
+}
+
+func (m replayModel) Init() tea.Cmd {
+	return m.scheduleNext()
+}
+
+func (m replayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		}
+
+	case replayTickMsg:
+		if m.idx >= len(m.frames)-1 {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		m.idx++
+		return m, m.scheduleNext()
+	}
+	return m, nil
+}
+
+func (m replayModel) View() string {
+	if m.quitting || len(m.frames) == 0 {
+		return "Bye! Thanks for watching the show.\n"
+	}
+	return m.frames[m.idx].Data
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pehlicd/firework/simulation"
+)
+
+// ShellType identifies a firework explosion pattern.
+type ShellType int
+
+const (
+	ShellPeony ShellType = iota
+	ShellRing
+	ShellWillow
+	ShellChrysanthemum
+	ShellPalm
+	ShellCrossette
+)
+
+// shellNames maps a ShellType to its CLI/display name, in declaration order.
+var shellNames = [...]string{"peony", "ring", "willow", "chrysanthemum", "palm", "crossette"}
+
+// String returns the shell's CLI name.
+func (s ShellType) String() string {
+	if int(s) < 0 || int(s) >= len(shellNames) {
+		return "unknown"
+	}
+	return shellNames[s]
+}
+
+// parseShellType looks up a ShellType by its CLI name.
+func parseShellType(name string) (ShellType, bool) {
+	for i, n := range shellNames {
+		if n == name {
+			return ShellType(i), true
+		}
+	}
+	return 0, false
+}
+
+// randomShellType picks a shell type uniformly at random.
+func randomShellType() ShellType {
+	return ShellType(rand.Intn(len(shellNames)))
+}
+
+// newBurstParticle wraps simulation.NewParticle, converting the per-tick
+// speed and gravity units used throughout this file into the per-second
+// units harmonica expects.
+func newBurstParticle(x, y, vx, vy, gravity float64, char string, color lipgloss.Color, lifespan int) *simulation.Particle {
+	return simulation.NewParticle(x, y, vx*fps, vy*fps, gravity*fps*fps, char, color, lifespan, fps)
+}
+
+// spawnShell builds the particles for a single firework burst of the given
+// kind, centered at (x, y) and tinted with color.
+func spawnShell(kind ShellType, x, y float64, color lipgloss.Color) []*simulation.Particle {
+	switch kind {
+	case ShellRing:
+		return spawnRing(x, y, color)
+	case ShellWillow:
+		return spawnWillow(x, y, color)
+	case ShellChrysanthemum:
+		return spawnChrysanthemum(x, y, color)
+	case ShellPalm:
+		return spawnPalm(x, y, color)
+	case ShellCrossette:
+		return spawnCrossette(x, y, color)
+	default:
+		return spawnPeony(x, y, color)
+	}
+}
+
+// spawnPeony is the classic uniform sphere of sparks.
+func spawnPeony(x, y float64, color lipgloss.Color) []*simulation.Particle {
+	n := rand.Intn(20) + 30 // 30 to 49 particles
+	particles := make([]*simulation.Particle, 0, n)
+	for i := 0; i < n; i++ {
+		angle := (2 * math.Pi / float64(n)) * float64(i)
+		speed := rand.Float64()*2.5 + 1.0
+		particles = append(particles, newBurstParticle(
+			x, y,
+			math.Cos(angle)*speed, math.Sin(angle)*speed*0.5,
+			0.08, "*", color, rand.Intn(20)+15,
+		))
+	}
+	return particles
+}
+
+// spawnRing is a tight, near-perfect circle: low speed variance and almost
+// no angular jitter.
+func spawnRing(x, y float64, color lipgloss.Color) []*simulation.Particle {
+	const n = 40
+	particles := make([]*simulation.Particle, 0, n)
+	for i := 0; i < n; i++ {
+		jitter := (rand.Float64() - 0.5) * 0.05
+		angle := (2*math.Pi/float64(n))*float64(i) + jitter
+		speed := 2.2 + (rand.Float64()-0.5)*0.2
+		particles = append(particles, newBurstParticle(
+			x, y,
+			math.Cos(angle)*speed, math.Sin(angle)*speed*0.5,
+			0.08, "*", color, rand.Intn(6)+24,
+		))
+	}
+	return particles
+}
+
+// spawnWillow throws out a few slow, heavy sparks with long lifespans that
+// arc over and droop back down like willow branches.
+func spawnWillow(x, y float64, color lipgloss.Color) []*simulation.Particle {
+	n := rand.Intn(10) + 20 // 20 to 29 particles
+	particles := make([]*simulation.Particle, 0, n)
+	for i := 0; i < n; i++ {
+		angle := (2 * math.Pi / float64(n)) * float64(i)
+		speed := rand.Float64()*1.0 + 0.8
+		particles = append(particles, newBurstParticle(
+			x, y,
+			math.Cos(angle)*speed, math.Sin(angle)*speed*0.5,
+			0.16, "*", color, rand.Intn(20)+40,
+		))
+	}
+	return particles
+}
+
+// spawnChrysanthemum layers two concentric rings sharing a color at
+// different speeds for a dense, multi-layered bloom.
+func spawnChrysanthemum(x, y float64, color lipgloss.Color) []*simulation.Particle {
+	const inner, outer = 24, 36
+	particles := make([]*simulation.Particle, 0, inner+outer)
+	for i := 0; i < inner; i++ {
+		angle := (2 * math.Pi / float64(inner)) * float64(i)
+		speed := 1.2 + rand.Float64()*0.3
+		particles = append(particles, newBurstParticle(
+			x, y,
+			math.Cos(angle)*speed, math.Sin(angle)*speed*0.5,
+			0.08, "*", color, rand.Intn(15)+20,
+		))
+	}
+	for i := 0; i < outer; i++ {
+		angle := (2 * math.Pi / float64(outer)) * float64(i)
+		speed := 2.6 + rand.Float64()*0.4
+		particles = append(particles, newBurstParticle(
+			x, y,
+			math.Cos(angle)*speed, math.Sin(angle)*speed*0.5,
+			0.08, "*", color, rand.Intn(15)+20,
+		))
+	}
+	return particles
+}
+
+// spawnPalm throws a handful of thick sparks mostly upward, like the fronds
+// of a palm tree, before gravity pulls them back down.
+func spawnPalm(x, y float64, color lipgloss.Color) []*simulation.Particle {
+	n := rand.Intn(4) + 6 // 6 to 9 fronds
+	particles := make([]*simulation.Particle, 0, n)
+	for i := 0; i < n; i++ {
+		angle := -math.Pi/2 + (rand.Float64()-0.5)*(math.Pi/2)
+		speed := rand.Float64()*1.5 + 2.5
+		particles = append(particles, newBurstParticle(
+			x, y,
+			math.Cos(angle)*speed, math.Sin(angle)*speed,
+			0.12, "#", color, rand.Intn(15)+30,
+		))
+	}
+	return particles
+}
+
+// spawnCrossette bursts into a ring of particles that each split into 4
+// sub-sparks once they reach the midpoint of their lifespan.
+func spawnCrossette(x, y float64, color lipgloss.Color) []*simulation.Particle {
+	n := rand.Intn(10) + 16 // 16 to 25 particles
+	particles := make([]*simulation.Particle, 0, n)
+	for i := 0; i < n; i++ {
+		angle := (2 * math.Pi / float64(n)) * float64(i)
+		speed := rand.Float64()*1.5 + 1.5
+		lifespan := rand.Intn(10) + 20
+		p := newBurstParticle(
+			x, y,
+			math.Cos(angle)*speed, math.Sin(angle)*speed*0.5,
+			0.08, "*", color, lifespan,
+		)
+		p.SplitAt = lifespan / 2
+		p.Split = crossetteSplit(color)
+		particles = append(particles, p)
+	}
+	return particles
+}
+
+// crossetteSplit returns a split callback that throws 4 short-lived sparks
+// outward from wherever the parent particle happens to be at split time.
+func crossetteSplit(color lipgloss.Color) func(p *simulation.Particle) []*simulation.Particle {
+	return func(p *simulation.Particle) []*simulation.Particle {
+		x, y := p.Position()
+		children := make([]*simulation.Particle, 0, 4)
+		for i := 0; i < 4; i++ {
+			angle := (math.Pi/2)*float64(i) + rand.Float64()*0.3
+			speed := rand.Float64()*1.0 + 1.0
+			children = append(children, newBurstParticle(
+				x, y,
+				math.Cos(angle)*speed, math.Sin(angle)*speed*0.5,
+				0.08, "*", color, rand.Intn(8)+8,
+			))
+		}
+		return children
+	}
+}
@@ -0,0 +1,218 @@
+// Package simulation provides a small, reusable particle-physics engine for
+// firework effects. Positions are tracked as floats via harmonica.Projectile
+// so motion stays frame-rate independent and smooth between terminal cells.
+package simulation
+
+import (
+	"github.com/charmbracelet/harmonica"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Frame describes the bounds particles are simulated and rendered within.
+type Frame struct {
+	Width, Height int
+}
+
+// Offset is an (X, Y) adjustment layered on top of an underlying
+// harmonica.Projectile's position. Particle and the main package's rocket
+// both carry one, so a terminal resize can reposition a live particle or
+// rocket without having to reconstruct its projectile.
+type Offset struct {
+	X, Y float64
+}
+
+// Position returns base's position adjusted by the offset.
+func (o Offset) Position(base *harmonica.Projectile) (x, y float64) {
+	pt := base.Position()
+	return pt.X + o.X, pt.Y + o.Y
+}
+
+// Rescale updates the offset so the current position (base plus the
+// existing offset) scales by (scaleX, scaleY) relative to base.
+func (o *Offset) Rescale(base *harmonica.Projectile, scaleX, scaleY float64) {
+	x, y := o.Position(base)
+	pt := base.Position()
+	o.X = x*scaleX - pt.X
+	o.Y = y*scaleY - pt.Y
+}
+
+// Clamp keeps the offset position within [0, width) x [0, height).
+func (o *Offset) Clamp(base *harmonica.Projectile, width, height int) {
+	x, y := o.Position(base)
+	switch {
+	case x < 0:
+		x = 0
+	case x > float64(width-1):
+		x = float64(width - 1)
+	}
+	switch {
+	case y < 0:
+		y = 0
+	case y > float64(height-1):
+		y = float64(height - 1)
+	}
+	pt := base.Position()
+	o.X = x - pt.X
+	o.Y = y - pt.Y
+}
+
+// Particle is a single point of light in a firework effect: a rocket in
+// flight or a spark thrown out by an explosion.
+type Particle struct {
+	Physics     *harmonica.Projectile
+	Char        string
+	Color       lipgloss.Color
+	Lifespan    int
+	MaxLifespan int
+
+	// SplitAt and Split let a particle spawn children partway through its
+	// life (e.g. a crossette shell breaking into sub-sparks). Split fires
+	// once, the first time Lifespan drops to SplitAt or below.
+	SplitAt int
+	Split   func(p *Particle) []*Particle
+
+	// Offset exists purely so a terminal resize can reposition a live
+	// particle without having to reconstruct its projectile.
+	Offset Offset
+}
+
+// NewParticle builds a Particle whose motion is governed by harmonica's
+// projectile physics. Position is in cells, velocity is in cells/sec,
+// gravity is in cells/sec^2, and fps determines the simulation's fixed time
+// step.
+func NewParticle(x, y, vx, vy, gravity float64, char string, color lipgloss.Color, lifespan, fps int) *Particle {
+	return &Particle{
+		Physics: harmonica.NewProjectile(
+			harmonica.FPS(fps),
+			harmonica.Point{X: x, Y: y},
+			harmonica.Vector{X: vx, Y: vy},
+			harmonica.Vector{Y: gravity},
+		),
+		Char:        char,
+		Color:       color,
+		Lifespan:    lifespan,
+		MaxLifespan: lifespan,
+	}
+}
+
+// Position returns the particle's current float coordinates.
+func (p *Particle) Position() (x, y float64) {
+	return p.Offset.Position(p.Physics)
+}
+
+// Rescale repositions the particle proportionally to a frame resize,
+// scaling its current position by (scaleX, scaleY) instead of letting it
+// drift off-screen or bunch up against the old bounds.
+func (p *Particle) Rescale(scaleX, scaleY float64) {
+	p.Offset.Rescale(p.Physics, scaleX, scaleY)
+}
+
+// Clamp keeps the particle's position within [0, width) x [0, height).
+func (p *Particle) Clamp(width, height int) {
+	p.Offset.Clamp(p.Physics, width, height)
+}
+
+// Update advances the particle's physics by one simulation step and ages it.
+func (p *Particle) Update() {
+	p.Physics.Update()
+	p.Lifespan--
+}
+
+// Alive reports whether the particle still has life left to render.
+func (p *Particle) Alive() bool {
+	return p.Lifespan > 0
+}
+
+// fadedChar thins the particle's glyph out as it nears the end of its
+// lifespan, giving explosions a dying flicker instead of a hard cutoff.
+func (p *Particle) fadedChar() string {
+	if p.MaxLifespan <= 0 {
+		return p.Char
+	}
+	switch alpha := float64(p.Lifespan) / float64(p.MaxLifespan); {
+	case alpha < 0.2:
+		return " "
+	case alpha < 0.5:
+		return "."
+	default:
+		return p.Char
+	}
+}
+
+// System owns every live particle for a given frame and knows how to step
+// and render them. A bubbletea model can hold a single *System for its
+// particles rather than juggling a particle slice itself.
+type System struct {
+	Particles []*Particle
+	Frame     Frame
+
+	// Budget caps how many particles the system will hold at once, so a
+	// finale's cascade of bursts (and any mid-life splits they trigger)
+	// can't choke the terminal. Budget <= 0 means unlimited.
+	Budget int
+}
+
+// NewSystem returns an empty System bounded to the given frame, holding at
+// most budget particles at once.
+func NewSystem(frame Frame, budget int) *System {
+	return &System{Frame: frame, Budget: budget}
+}
+
+// Spawn adds a particle to the system, unless it's already at budget.
+func (s *System) Spawn(p *Particle) {
+	if s.Budget > 0 && len(s.Particles) >= s.Budget {
+		return
+	}
+	s.Particles = append(s.Particles, p)
+}
+
+// Update steps every particle forward one frame, drops the ones that have
+// run out of lifespan, and spawns any children produced by a split, subject
+// to the same budget Spawn enforces.
+func (s *System) Update() {
+	var spawned []*Particle
+	alive := s.Particles[:0]
+	for _, p := range s.Particles {
+		p.Update()
+		if p.Split != nil && p.Lifespan <= p.SplitAt {
+			spawned = append(spawned, p.Split(p)...)
+			p.Split = nil
+		}
+		if p.Alive() {
+			alive = append(alive, p)
+		}
+	}
+
+	if s.Budget > 0 {
+		if room := s.Budget - len(alive); room < len(spawned) {
+			if room < 0 {
+				room = 0
+			}
+			spawned = spawned[:room]
+		}
+	}
+	s.Particles = append(alive, spawned...)
+}
+
+// Render draws every live particle into buf, a Frame.Height x Frame.Width
+// buffer of already-styled strings, ready to be joined into rows. buf is
+// cleared and reused in place so callers can hold onto the same buffer
+// across frames instead of allocating one every call.
+func (s *System) Render(buf [][]string) [][]string {
+	for i := range buf {
+		for j := range buf[i] {
+			buf[i][j] = " "
+		}
+	}
+
+	for _, p := range s.Particles {
+		x, y := p.Position()
+		row, col := int(y), int(x)
+		if row >= 0 && row < len(buf) && col >= 0 && col < len(buf[row]) {
+			style := lipgloss.NewStyle().Foreground(p.Color)
+			buf[row][col] = style.Render(p.fadedChar())
+		}
+	}
+
+	return buf
+}
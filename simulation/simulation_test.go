@@ -0,0 +1,44 @@
+package simulation
+
+import "testing"
+
+const testFPS = 30
+
+func TestParticleTrajectoryIsDeterministic(t *testing.T) {
+	const lifespan = 40 // long enough for gravity to reverse the initial upward velocity
+	p := NewParticle(10, 20, 2, -5, 9.8, "*", "196", lifespan, testFPS)
+
+	var positions [][2]float64
+	for p.Alive() {
+		x, y := p.Position()
+		positions = append(positions, [2]float64{x, y})
+		p.Update()
+	}
+
+	if len(positions) != lifespan {
+		t.Fatalf("expected %d steps before lifespan expired, got %d", lifespan, len(positions))
+	}
+	if positions[0] != [2]float64{10, 20} {
+		t.Fatalf("expected particle to start at spawn point, got %v", positions[0])
+	}
+	if last := positions[len(positions)-1][1]; last <= positions[0][1] {
+		t.Fatalf("expected gravity to pull the particle back down past its start over its lifespan, got y=%v", last)
+	}
+}
+
+func TestSystemUpdateDropsExpiredParticles(t *testing.T) {
+	s := NewSystem(Frame{Width: 40, Height: 20}, 0)
+	s.Spawn(NewParticle(1, 1, 0, 0, 0, "*", "196", 1, testFPS))
+	s.Spawn(NewParticle(2, 2, 0, 0, 0, "*", "208", 3, testFPS))
+
+	s.Update()
+	if len(s.Particles) != 1 {
+		t.Fatalf("expected 1 particle to survive the first update, got %d", len(s.Particles))
+	}
+
+	s.Update()
+	s.Update()
+	if len(s.Particles) != 0 {
+		t.Fatalf("expected all particles to expire, got %d left", len(s.Particles))
+	}
+}